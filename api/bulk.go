@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//defaultBulkWorkers is the default number of orders PlaceOrders and CancelOrders submit concurrently.
+const defaultBulkWorkers = 8
+
+//defaultBulkInterval is the minimum spacing enforced between successive submissions by a single worker, to
+//stay under the Stockfighter API's rate limits when placing many orders back-to-back.
+const defaultBulkInterval = 50 * time.Millisecond
+
+//OrderSpec describes a single order to be submitted as part of a PlaceOrders batch.
+type OrderSpec struct {
+	Symbol    string
+	Venue     string
+	Price     int
+	Quantity  int
+	Direction orderDirection
+	OrderType orderType
+}
+
+//OrderResult is the outcome of a single order within a PlaceOrders batch: exactly one of Order and Err is set.
+type OrderResult struct {
+	Order Order
+	Err   error
+}
+
+//CancelResult is the outcome of a single cancellation within a CancelOrders batch: exactly one of Order and
+//Err is set.
+type CancelResult struct {
+	Order Order
+	Err   error
+}
+
+//PlaceOrders submits reqs concurrently over a bounded worker pool (defaultBulkWorkers workers) and returns one
+//OrderResult per request, in the same order as reqs. A per-order failure is reported in that order's Err
+//field rather than failing the whole batch; PlaceOrders itself only returns a non-nil error if ctx is
+//canceled before submission could complete.
+func (i *Instance) PlaceOrders(ctx context.Context, reqs []OrderSpec) ([]OrderResult, error) {
+	results := make([]OrderResult, len(reqs))
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < defaultBulkWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range work {
+				spec := reqs[idx]
+				i.RLock()
+				account := i.account
+				i.RUnlock()
+				payload := orderRequest{
+					Account:   account,
+					Venue:     spec.Venue,
+					Symbol:    spec.Symbol,
+					Price:     spec.Price,
+					Quantity:  spec.Quantity,
+					Direction: spec.Direction,
+					OrderType: spec.OrderType,
+				}
+				url := baseURL + "venues/" + spec.Venue + "/stocks/" + spec.Symbol + "/orders"
+				order, err := i.doOrderRequest(ctx, "POST", url, payload, "")
+				results[idx] = OrderResult{Order: order, Err: err}
+				time.Sleep(defaultBulkInterval)
+			}
+		}()
+	}
+
+	for idx := range reqs {
+		select {
+		case work <- idx:
+		case <-ctx.Done():
+			for j := idx; j < len(reqs); j++ {
+				results[j] = OrderResult{Err: ctx.Err()}
+			}
+			close(work)
+			wg.Wait()
+			return results, ctx.Err()
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return results, nil
+}
+
+//CancelOrders cancels every order ID in ids concurrently over a bounded worker pool (defaultBulkWorkers
+//workers) and returns one CancelResult per ID, in the same order as ids. A per-order failure is reported in
+//that order's Err field rather than failing the whole batch.
+func (i *Instance) CancelOrders(ctx context.Context, ids []int) []CancelResult {
+	results := make([]CancelResult, len(ids))
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < defaultBulkWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range work {
+				order, err := i.CancelOrderContext(ctx, ids[idx])
+				results[idx] = CancelResult{Order: order, Err: err}
+				time.Sleep(defaultBulkInterval)
+			}
+		}()
+	}
+
+	for idx := range ids {
+		select {
+		case work <- idx:
+		case <-ctx.Done():
+			for j := idx; j < len(ids); j++ {
+				results[j] = CancelResult{Err: ctx.Err()}
+			}
+			close(work)
+			wg.Wait()
+			return results
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func newTestInstanceWithTransport(rt roundTripFunc) *Instance {
+	i := NewTestInstance()
+	i.c.Transport = rt
+	return i
+}
+
+func jsonResponse(status int, v interface{}) *http.Response {
+	b, _ := json.Marshal(v)
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Body:       io.NopCloser(bytes.NewReader(b)),
+		Header:     make(http.Header),
+	}
+}
+
+func TestPlaceOrdersPartialFailure(t *testing.T) {
+	var calls int32
+	i := newTestInstanceWithTransport(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n%2 == 0 {
+			return jsonResponse(500, errorResult{Error: "boom"}), nil
+		}
+		return jsonResponse(200, Order{ID: int(n)}), nil
+	})
+
+	reqs := make([]OrderSpec, 10)
+	for idx := range reqs {
+		reqs[idx] = OrderSpec{Symbol: "FOOBAR", Venue: "TESTEX", Price: 100, Quantity: 1, Direction: Buy, OrderType: Limit}
+	}
+
+	results, err := i.PlaceOrders(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("PlaceOrders returned error: %v", err)
+	}
+	if len(results) != len(reqs) {
+		t.Fatalf("got %d results, want %d", len(results), len(reqs))
+	}
+	var ok, failed int
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		} else {
+			ok++
+		}
+	}
+	if ok == 0 || failed == 0 {
+		t.Fatalf("expected a mix of successes and failures, got %d ok, %d failed", ok, failed)
+	}
+}
+
+//TestPlaceOrdersCtxCancellation exercises the backfill path: cancel ctx once every worker is blocked on an
+//in-flight request, which forces the dispatch loop's "case <-ctx.Done()" branch for every undispatched index.
+func TestPlaceOrdersCtxCancellation(t *testing.T) {
+	var started int32
+	unblock := make(chan struct{})
+	i := newTestInstanceWithTransport(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&started, 1)
+		<-unblock
+		return jsonResponse(200, Order{}), nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reqs := make([]OrderSpec, defaultBulkWorkers+5)
+	for idx := range reqs {
+		reqs[idx] = OrderSpec{Symbol: "FOOBAR", Venue: "TESTEX"}
+	}
+
+	type outcome struct {
+		results []OrderResult
+		err     error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		results, err := i.PlaceOrders(ctx, reqs)
+		done <- outcome{results, err}
+	}()
+
+	for atomic.LoadInt32(&started) < defaultBulkWorkers {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	close(unblock)
+
+	out := <-done
+	if out.err != context.Canceled {
+		t.Fatalf("got err %v, want context.Canceled", out.err)
+	}
+	if len(out.results) != len(reqs) {
+		t.Fatalf("got %d results, want %d", len(out.results), len(reqs))
+	}
+	var backfilled int
+	for _, r := range out.results {
+		if r.Err == context.Canceled {
+			backfilled++
+		}
+	}
+	if backfilled == 0 {
+		t.Fatalf("expected at least one result backfilled with ctx.Err(), got %+v", out.results)
+	}
+}
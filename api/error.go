@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+//Error is returned by the ctx-accepting Instance methods (NewOrderContext, CancelOrderContext, ...) in place
+//of the legacy setErr/Err pattern, so concurrent callers can tell which call failed and whether retrying is
+//worthwhile.
+type Error struct {
+	StatusCode int    //HTTP status code of the response, 0 if the request never got a response.
+	APIMessage string //the "error" field of the Stockfighter API's JSON error body, if any.
+	Op         string //the Instance method that produced the error, e.g. "NewOrder".
+	OrderID    int    //the order ID the call concerned, if known; 0 otherwise.
+	Retryable  bool   //true for network errors, HTTP 429 and HTTP 5xx.
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("api: %s: %s", e.Op, e.APIMessage)
+	}
+	return fmt.Sprintf("api: %s: %s (status %d)", e.Op, e.APIMessage, e.StatusCode)
+}
+
+func retryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+//RetryPolicy configures how the REST layer retries a request after a retryable failure. It is consulted
+//between attempts, never during the HTTP round trip itself; ctx cancellation is honored while sleeping.
+type RetryPolicy struct {
+	MaxAttempts    int           //total attempts including the first; <= 1 disables retrying.
+	InitialBackoff time.Duration //delay before the first retry.
+	MaxBackoff     time.Duration //backoff is capped here regardless of attempt count.
+	Jitter         float64       //fraction of the computed backoff to randomize, in [0,1].
+}
+
+//NoRetry disables retrying: every request is attempted exactly once.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+//DefaultRetryPolicy retries up to 3 times total with exponential backoff between 200ms and 5s, plus 20% jitter.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Jitter:         0.2,
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff << uint(attempt)
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		jitter := time.Duration(float64(d) * p.Jitter * (rand.Float64()*2 - 1))
+		d += jitter
+	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
+
+//WithRetry configures the retry policy used by the ctx-accepting Instance methods and returns i for chaining,
+//e.g. i = i.WithRetry(api.DefaultRetryPolicy). Instances default to NoRetry.
+func (i *Instance) WithRetry(policy RetryPolicy) *Instance {
+	i.Lock()
+	i.retry = policy
+	i.Unlock()
+	return i
+}
+
+func (i *Instance) retryPolicy() RetryPolicy {
+	i.RLock()
+	defer i.RUnlock()
+	return i.retry
+}
+
+//doHTTP executes req, retrying according to i's configured RetryPolicy when the failure is retryable
+//(network errors, HTTP 429, HTTP 5xx). newReq is called again to build a fresh request for each retry, since
+//an *http.Request's body cannot be replayed once consumed. The response body of a retried attempt is closed
+//before the next attempt runs, since only the response ultimately returned is the caller's to close. ctx
+//cancellation is honored between attempts.
+func (i *Instance) doHTTP(ctx context.Context, newReq func() (*http.Request, error)) (*http.Response, error) {
+	policy := i.retryPolicy()
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	var res *http.Response
+	var err error
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		var req *http.Request
+		req, err = newReq()
+		if err != nil {
+			return nil, err
+		}
+
+		res, err = i.c.Do(req)
+		retryable := err != nil || retryableStatus(res.StatusCode)
+		if !retryable || attempt == policy.MaxAttempts-1 {
+			return res, err
+		}
+		if res != nil {
+			res.Body.Close()
+		}
+
+		select {
+		case <-time.After(policy.backoff(attempt)):
+		case <-ctx.Done():
+			return res, ctx.Err()
+		}
+	}
+	return res, err
+}
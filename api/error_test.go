@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryableStatus(t *testing.T) {
+	cases := map[int]bool{
+		200: false,
+		404: false,
+		429: true,
+		500: true,
+		503: true,
+	}
+	for status, want := range cases {
+		if got := retryableStatus(status); got != want {
+			t.Errorf("retryableStatus(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Jitter: 0.5}
+	for attempt := 0; attempt < 5; attempt++ {
+		d := p.backoff(attempt)
+		if d < 0 {
+			t.Fatalf("backoff(%d) = %v, want >= 0", attempt, d)
+		}
+		max := p.MaxBackoff + time.Duration(float64(p.MaxBackoff)*p.Jitter)
+		if d > max {
+			t.Fatalf("backoff(%d) = %v, want <= %v", attempt, d, max)
+		}
+	}
+}
+
+//closeTrackingBody wraps a response body to record whether Close was called, so doHTTP's retry loop can be
+//checked for leaking the previous attempt's response.
+type closeTrackingBody struct {
+	io.Reader
+	closed int32
+}
+
+func (b *closeTrackingBody) Close() error {
+	atomic.StoreInt32(&b.closed, 1)
+	return nil
+}
+
+func TestDoHTTPClosesRetriedResponseBody(t *testing.T) {
+	var bodies []*closeTrackingBody
+	var calls int32
+	i := newTestInstanceWithTransport(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&calls, 1)
+		res := jsonResponse(500, errorResult{Error: "boom"})
+		body := &closeTrackingBody{Reader: res.Body}
+		res.Body = body
+		bodies = append(bodies, body)
+		if n == 3 {
+			res.StatusCode = 200
+		}
+		return res, nil
+	})
+	i.WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	res, err := i.doHTTP(context.Background(), func() (*http.Request, error) {
+		return http.NewRequest("GET", "http://example.invalid/", nil)
+	})
+	if err != nil {
+		t.Fatalf("doHTTP returned error: %v", err)
+	}
+	defer res.Body.Close()
+
+	if len(bodies) != 3 {
+		t.Fatalf("got %d attempts, want 3", len(bodies))
+	}
+	for idx, b := range bodies[:len(bodies)-1] {
+		if atomic.LoadInt32(&b.closed) == 0 {
+			t.Errorf("attempt %d's response body was not closed before retrying", idx)
+		}
+	}
+	if atomic.LoadInt32(&bodies[len(bodies)-1].closed) != 0 {
+		t.Errorf("final response body was closed by doHTTP; it is the caller's to close")
+	}
+}
+
+//TestDoHTTPRetriesTransportErrorWithoutPanic covers an attempt that fails before a response even exists
+//(i.c.Do returning a nil *http.Response alongside a non-nil error, e.g. a dial failure): doHTTP must not try
+//to close a nil response body when deciding to retry.
+func TestDoHTTPRetriesTransportErrorWithoutPanic(t *testing.T) {
+	var calls int32
+	i := newTestInstanceWithTransport(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n < 3 {
+			return nil, errors.New("dial tcp: connection refused")
+		}
+		return jsonResponse(200, Order{}), nil
+	})
+	i.WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	res, err := i.doHTTP(context.Background(), func() (*http.Request, error) {
+		return http.NewRequest("GET", "http://example.invalid/", nil)
+	})
+	if err != nil {
+		t.Fatalf("doHTTP returned error: %v", err)
+	}
+	defer res.Body.Close()
+	if calls != 3 {
+		t.Fatalf("got %d attempts, want 3", calls)
+	}
+}
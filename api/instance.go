@@ -0,0 +1,245 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//baseURL is the root of the Stockfighter REST API that every Instance method builds its request URL from.
+const baseURL = "https://api.stockfighter.io/ob/api/"
+
+//errorResult decodes the JSON error body the Stockfighter API returns alongside a non-200 status.
+type errorResult struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+//apiError builds an error from the Stockfighter API's error message and the HTTP status line, falling back to
+//a generic message when the API didn't send one.
+func apiError(message, status string) error {
+	if message == "" {
+		message = "no error message"
+	}
+	return fmt.Errorf("api: %s: %s", status, message)
+}
+
+//Instance is the basic unit of operation for all API calls: it holds the account/venue/symbol a call defaults
+//to, the HTTP client and headers used to make it, and the per-Instance state (retry policy, client order IDs,
+//order tracking) the rest of the package hangs off it. The zero value is not usable; construct one with
+//NewInstance or NewTestInstance.
+type Instance struct {
+	sync.RWMutex
+	account string
+	venue   string
+	symbol  string
+
+	h http.Header
+	c *http.Client
+
+	err error
+
+	retry          RetryPolicy
+	clientOrderIDs map[string]int
+	orderBook      *OrderBook
+}
+
+//NewInstance creates a new API instance for account on venue trading symbol.
+func NewInstance(account, venue, symbol string) *Instance {
+	return &Instance{
+		account: account,
+		venue:   venue,
+		symbol:  symbol,
+		h:       http.Header{},
+		c:       &http.Client{},
+		retry:   NoRetry,
+	}
+}
+
+//NewTestInstance creates a new API instance with the venue/symbol Stockfighter reserves for testing.
+func NewTestInstance() *Instance {
+	return NewInstance("EXB123456", "TESTEX", "FOOBAR")
+}
+
+//SetAPIKey changes the API key sent with every subsequent request.
+func (i *Instance) SetAPIKey(apiKey string) {
+	i.Lock()
+	defer i.Unlock()
+	i.h.Set("X-Starfighter-Authorization", apiKey)
+}
+
+//setErr records err on i for the legacy non-Context methods to surface via Err, if err is non-nil.
+func (i *Instance) setErr(err error) {
+	if err == nil {
+		return
+	}
+	i.Lock()
+	i.err = err
+	i.Unlock()
+}
+
+//Err returns the error from the most recent legacy (non-Context) call, or nil if it succeeded.
+func (i *Instance) Err() error {
+	i.RLock()
+	defer i.RUnlock()
+	return i.err
+}
+
+//Heartbeat checks if the API is up.
+//See https://starfighter.readme.io/docs/heartbeat for further info about the actual API call.
+func (i *Instance) Heartbeat() bool {
+	ok, err := i.heartbeat(context.Background(), baseURL+"heartbeat")
+	i.setErr(err)
+	return ok
+}
+
+//VenueHeartbeat checks if the current venue is up.
+//See https://starfighter.readme.io/docs/venue-healthcheck for further info about the actual API call.
+func (i *Instance) VenueHeartbeat() bool {
+	i.RLock()
+	url := baseURL + "venues/" + i.venue + "/heartbeat"
+	i.RUnlock()
+
+	ok, err := i.heartbeat(context.Background(), url)
+	i.setErr(err)
+	return ok
+}
+
+func (i *Instance) heartbeat(ctx context.Context, url string) (bool, error) {
+	res, err := i.doHTTP(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
+	if err != nil {
+		return false, err
+	}
+	var v errorResult
+	if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+		return false, err
+	}
+	return v.Ok, nil
+}
+
+type availableStocksResult struct {
+	Ok      bool    `json:"ok"`
+	Error   string  `json:"error"`
+	Symbols []Stock `json:"symbols"`
+}
+
+//Stock identifies a stock tradable on a venue.
+type Stock struct {
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+}
+
+//AvailableStocks returns the stocks tradable on the current venue.
+//See https://starfighter.readme.io/docs/list-stocks-on-venue for further info about the actual API call.
+func (i *Instance) AvailableStocks() []Stock {
+	i.RLock()
+	url := baseURL + "venues/" + i.venue + "/stocks"
+	i.RUnlock()
+
+	res, err := i.doHTTP(context.Background(), func() (*http.Request, error) {
+		return http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	})
+	if err != nil {
+		i.setErr(err)
+		return nil
+	}
+	var v availableStocksResult
+	if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+		i.setErr(err)
+		return nil
+	}
+	if !v.Ok {
+		i.setErr(apiError(v.Error, res.Status))
+		return nil
+	}
+	return v.Symbols
+}
+
+//OrderbookResult is the current state of the order book for the current stock on the current venue.
+type OrderbookResult struct {
+	Ok     bool        `json:"ok"`
+	Error  string      `json:"error"`
+	Venue  string      `json:"venue"`
+	Symbol string      `json:"symbol"`
+	Bids   []OrderLine `json:"bids"`
+	Asks   []OrderLine `json:"asks"`
+	TS     time.Time   `json:"ts"`
+}
+
+//OrderLine is a single price level of an OrderbookResult.
+type OrderLine struct {
+	Price int  `json:"price"`
+	Qty   int  `json:"qty"`
+	IsBuy bool `json:"isBuy"`
+}
+
+//Orderbook returns the current order book for the current stock on the current venue.
+//See https://starfighter.readme.io/docs/getting-the-orderbook-for-a-stock for further info about the actual API call.
+func (i *Instance) Orderbook() (v OrderbookResult) {
+	i.RLock()
+	url := baseURL + "venues/" + i.venue + "/stocks/" + i.symbol
+	i.RUnlock()
+
+	res, err := i.doHTTP(context.Background(), func() (*http.Request, error) {
+		return http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	})
+	if err != nil {
+		i.setErr(err)
+		return
+	}
+	if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+		i.setErr(err)
+		return
+	}
+	if !v.Ok {
+		i.setErr(apiError(v.Error, res.Status))
+	}
+	return
+}
+
+//Quote is a snapshot of the current bid/ask/last-trade state of the current stock on the current venue.
+type Quote struct {
+	Ok        bool      `json:"ok"`
+	Error     string    `json:"error"`
+	Venue     string    `json:"venue"`
+	Symbol    string    `json:"symbol"`
+	Bid       int       `json:"bid"`
+	Ask       int       `json:"ask"`
+	BidSize   int       `json:"bidSize"`
+	AskSize   int       `json:"askSize"`
+	BidDepth  int       `json:"bidDepth"`
+	AskDepth  int       `json:"askDepth"`
+	LastPrice int       `json:"last"`
+	LastSize  int       `json:"lastSize"`
+	LastTrade time.Time `json:"lastTrade"`
+	QuoteTime time.Time `json:"quoteTime"`
+}
+
+//Quote returns the current quote for the current stock on the current venue.
+//See https://starfighter.readme.io/docs/a-quote-for-a-stock for further info about the actual API call.
+func (i *Instance) Quote() (v Quote) {
+	i.RLock()
+	url := baseURL + "venues/" + i.venue + "/stocks/" + i.symbol + "/quote"
+	i.RUnlock()
+
+	res, err := i.doHTTP(context.Background(), func() (*http.Request, error) {
+		return http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	})
+	if err != nil {
+		i.setErr(err)
+		return
+	}
+	if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+		i.setErr(err)
+		return
+	}
+	if !v.Ok {
+		i.setErr(apiError(v.Error, res.Status))
+	}
+	return
+}
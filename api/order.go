@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -54,82 +55,181 @@ type Order struct {
 	Fills            []Fill         `json:"fills"`
 	TotalFilled      int            `json:"totalFilled"`
 	Open             bool           `json:"open"`
+
+	//State is derived from Open, TotalFilled and OriginalQuantity after decoding; see OrderState.
+	State OrderState `json:"-"`
+
+	//ClientOrderID is not part of the Stockfighter API; it is stamped on by OrderRequestBuilder.Do from the
+	//caller-supplied id passed to OrderRequestBuilder.ClientOrderID, for local idempotency tracking.
+	ClientOrderID string `json:"-"`
+}
+
+//UnmarshalJSON decodes an Order from the Stockfighter API's JSON representation, accepting both the
+//documented "orignialQty" field and the correctly-spelled "originalQty" in case the API is ever fixed, and
+//derives State from the decoded fields.
+func (o *Order) UnmarshalJSON(data []byte) error {
+	type orderAlias Order
+	aux := struct {
+		OriginalQuantityAlt int `json:"originalQty"`
+		*orderAlias
+	}{orderAlias: (*orderAlias)(o)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if o.OriginalQuantity == 0 && aux.OriginalQuantityAlt != 0 {
+		o.OriginalQuantity = aux.OriginalQuantityAlt
+	}
+	o.State = deriveOrderState(*o)
+	return nil
 }
 
 //NewOrder makes a new order and submits it to the API. See the package constants for available orderDirection and orderType types.
 //NewOrder returns a Order struct of the created order.
 //See https://starfighter.readme.io/docs/place-new-order for further info about the actual API call.
 func (i *Instance) NewOrder(price int, quantity int, direction orderDirection, orderType orderType) (v Order) {
+	v, err := i.NewOrderContext(context.Background(), price, quantity, direction, orderType)
+	i.setErr(err)
+	return
+}
+
+//NewOrderContext behaves like NewOrder but carries ctx through request marshaling, submission and response
+//decoding, so a caller can cancel or time-bound the call via ctx instead of waiting for i.c.Do to return.
+//See https://starfighter.readme.io/docs/place-new-order for further info about the actual API call.
+func (i *Instance) NewOrderContext(ctx context.Context, price int, quantity int, direction orderDirection, orderType orderType) (v Order, err error) {
 	i.RLock()
-	b, jsonErr := json.Marshal(orderRequest{i.account, i.venue, i.symbol, price, quantity, direction, orderType})
-	i.setErr(jsonErr)
+	req := orderRequest{i.account, i.venue, i.symbol, price, quantity, direction, orderType}
 	url := baseURL + "venues/" + i.venue + "/stocks/" + i.symbol + "/orders"
 	i.RUnlock()
-	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(b))
-	req.Header = i.h
-
-	res, httpErr := i.c.Do(req)
-	i.setErr(httpErr)
-
-	dec := json.NewDecoder(res.Body)
-	if res.StatusCode == 200 {
-		jsonErr = dec.Decode(&v)
-	} else {
-		var v errorResult
-		jsonErr = dec.Decode(&v)
-		i.setErr(apiError(v.Error, res.Status))
-	}
-
-	i.setErr(jsonErr)
-	return
+	return i.doOrderRequest(ctx, "POST", url, req, "")
 }
 
 //CancelOrder cancels an order given it's id.
 //See https://starfighter.readme.io/docs/cancel-an-order for further info about the actual API call.
 func (i *Instance) CancelOrder(ID int) (v Order) {
+	v, err := i.CancelOrderContext(context.Background(), ID)
+	i.setErr(err)
+	return
+}
+
+//CancelOrderContext behaves like CancelOrder but accepts a ctx that is honored for the duration of the HTTP call.
+//See https://starfighter.readme.io/docs/cancel-an-order for further info about the actual API call.
+func (i *Instance) CancelOrderContext(ctx context.Context, ID int) (v Order, err error) {
 	i.RLock()
-	req, _ := http.NewRequest("DELETE", baseURL+"venues/"+i.venue+"/stocks/"+i.symbol+"/orders/"+strconv.Itoa(ID), nil)
+	url := baseURL + "venues/" + i.venue + "/stocks/" + i.symbol + "/orders/" + strconv.Itoa(ID)
 	i.RUnlock()
-	req.Header = i.h
-	res, httpErr := i.c.Do(req)
-	i.setErr(httpErr)
+
+	res, httpErr := i.doHTTP(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		i.RLock()
+		req.Header = i.h
+		i.RUnlock()
+		return req, nil
+	})
+	if httpErr != nil {
+		return v, httpErr
+	}
 
 	dec := json.NewDecoder(res.Body)
-	var jsonErr error
 	if res.StatusCode == 200 {
-		jsonErr = dec.Decode(&v)
-	} else {
-		var v errorResult
-		jsonErr = dec.Decode(&v)
-		i.setErr(apiError(v.Error, res.Status))
+		err = dec.Decode(&v)
+		if err == nil {
+			i.trackOrder(v)
+		}
+		return
 	}
-
-	i.setErr(jsonErr)
-	return
+	var e errorResult
+	if decErr := dec.Decode(&e); decErr != nil {
+		return v, decErr
+	}
+	return v, &Error{StatusCode: res.StatusCode, APIMessage: e.Error, Op: "CancelOrder", OrderID: ID, Retryable: retryableStatus(res.StatusCode)}
 }
 
 //OrderStatus returns the current order status for the given order id.
 //See https://starfighter.readme.io/docs/status-for-an-existing-order for further info about the actual API call.
 func (i *Instance) OrderStatus(ID int) (v Order) {
+	v, err := i.OrderStatusContext(context.Background(), ID)
+	i.setErr(err)
+	return
+}
+
+//OrderStatusContext behaves like OrderStatus but accepts a ctx that is honored for the duration of the HTTP call.
+//See https://starfighter.readme.io/docs/status-for-an-existing-order for further info about the actual API call.
+func (i *Instance) OrderStatusContext(ctx context.Context, ID int) (v Order, err error) {
 	i.RLock()
-	req, _ := http.NewRequest("GET", baseURL+"venues/"+i.venue+"/stocks/"+i.symbol+"/orders/"+strconv.Itoa(ID), nil)
+	url := baseURL + "venues/" + i.venue + "/stocks/" + i.symbol + "/orders/" + strconv.Itoa(ID)
 	i.RUnlock()
-	req.Header = i.h
-	res, httpErr := i.c.Do(req)
-	i.setErr(httpErr)
+
+	res, httpErr := i.doHTTP(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		i.RLock()
+		req.Header = i.h
+		i.RUnlock()
+		return req, nil
+	})
+	if httpErr != nil {
+		return v, httpErr
+	}
 
 	dec := json.NewDecoder(res.Body)
-	var jsonErr error
 	if res.StatusCode == 200 {
-		jsonErr = dec.Decode(&v)
-	} else {
-		var v errorResult
-		jsonErr = dec.Decode(&v)
-		i.setErr(apiError(v.Error, res.Status))
+		err = dec.Decode(&v)
+		if err == nil {
+			i.trackOrder(v)
+		}
+		return
 	}
+	var e errorResult
+	if decErr := dec.Decode(&e); decErr != nil {
+		return v, decErr
+	}
+	return v, &Error{StatusCode: res.StatusCode, APIMessage: e.Error, Op: "OrderStatus", OrderID: ID, Retryable: retryableStatus(res.StatusCode)}
+}
 
-	i.setErr(jsonErr)
-	return
+//doOrderRequest marshals req, POSTs it to url with ctx honored for the duration of the call (retrying
+//according to i's RetryPolicy on transient failures), and decodes the resulting Order or API error. clientOrderID,
+//if non-empty, is stamped onto the decoded Order before it is handed to trackOrder, so an enabled OrderBook
+//sees it too. It is shared by NewOrderContext, PlaceOrders and the order builder's Do.
+func (i *Instance) doOrderRequest(ctx context.Context, method, url string, payload orderRequest, clientOrderID string) (v Order, err error) {
+	b, jsonErr := json.Marshal(payload)
+	if jsonErr != nil {
+		return v, jsonErr
+	}
+
+	res, httpErr := i.doHTTP(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		i.RLock()
+		req.Header = i.h
+		i.RUnlock()
+		return req, nil
+	})
+	if httpErr != nil {
+		return v, httpErr
+	}
+
+	dec := json.NewDecoder(res.Body)
+	if res.StatusCode == 200 {
+		err = dec.Decode(&v)
+		if err == nil {
+			v.ClientOrderID = clientOrderID
+			i.trackOrder(v)
+		}
+		return
+	}
+	var e errorResult
+	if decErr := dec.Decode(&e); decErr != nil {
+		return v, decErr
+	}
+	return v, &Error{StatusCode: res.StatusCode, APIMessage: e.Error, Op: "NewOrder", Retryable: retryableStatus(res.StatusCode)}
 }
 
 type allOrdersStatusResult struct {
@@ -141,53 +241,72 @@ type allOrdersStatusResult struct {
 //AccountOrderStatus returns the current status for all orders of the current account on the current venue.
 //See https://starfighter.readme.io/docs/status-for-all-orders for further info about the actual API call.
 func (i *Instance) AccountOrderStatus() []Order {
+	v, err := i.AccountOrderStatusContext(context.Background())
+	i.setErr(err)
+	return v
+}
+
+//AccountOrderStatusContext behaves like AccountOrderStatus but accepts a ctx that is honored for the duration
+//of the HTTP call.
+//See https://starfighter.readme.io/docs/status-for-all-orders for further info about the actual API call.
+func (i *Instance) AccountOrderStatusContext(ctx context.Context) ([]Order, error) {
 	i.RLock()
-	req, _ := http.NewRequest("GET", baseURL+"venues/"+i.venue+"/accounts/"+i.account+"/orders", nil)
+	url := baseURL + "venues/" + i.venue + "/accounts/" + i.account + "/orders"
 	i.RUnlock()
-	req.Header = i.h
-	res, httpErr := i.c.Do(req)
-	i.setErr(httpErr)
-
-	dec := json.NewDecoder(res.Body)
-	var jsonErr error
-
-	if res.StatusCode == 200 {
-		var v allOrdersStatusResult
-		jsonErr = dec.Decode(&v)
-		return v.Orders
-	}
-
-	var v errorResult
-	jsonErr = dec.Decode(&v)
-	i.setErr(apiError(v.Error, res.Status))
-
-	i.setErr(jsonErr)
-	return nil
+	return i.doOrdersListRequest(ctx, url)
 }
 
 //StockOrderStatus returns the current status for all orders of the current stock on the current venue and account.
 //See https://starfighter.readme.io/docs/status-for-all-orders-in-a-stock for further info about the actual API call.
 func (i *Instance) StockOrderStatus() []Order {
+	v, err := i.StockOrderStatusContext(context.Background())
+	i.setErr(err)
+	return v
+}
+
+//StockOrderStatusContext behaves like StockOrderStatus but accepts a ctx that is honored for the duration of
+//the HTTP call.
+//See https://starfighter.readme.io/docs/status-for-all-orders-in-a-stock for further info about the actual API call.
+func (i *Instance) StockOrderStatusContext(ctx context.Context) ([]Order, error) {
 	i.RLock()
-	req, _ := http.NewRequest("GET", baseURL+"venues/"+i.venue+"/accounts/"+i.account+"/stocks/"+i.symbol+"/orders", nil)
+	url := baseURL + "venues/" + i.venue + "/accounts/" + i.account + "/stocks/" + i.symbol + "/orders"
 	i.RUnlock()
-	req.Header = i.h
-	res, httpErr := i.c.Do(req)
-	i.setErr(httpErr)
+	return i.doOrdersListRequest(ctx, url)
+}
 
-	dec := json.NewDecoder(res.Body)
-	var jsonErr error
+//doOrdersListRequest GETs url with ctx honored for the duration of the call (retrying according to i's
+//RetryPolicy on transient failures) and decodes the resulting order list or API error. It is shared by
+//AccountOrderStatusContext and StockOrderStatusContext.
+func (i *Instance) doOrdersListRequest(ctx context.Context, url string) ([]Order, error) {
+	res, httpErr := i.doHTTP(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		i.RLock()
+		req.Header = i.h
+		i.RUnlock()
+		return req, nil
+	})
+	if httpErr != nil {
+		return nil, httpErr
+	}
 
+	dec := json.NewDecoder(res.Body)
 	if res.StatusCode == 200 {
 		var v allOrdersStatusResult
-		jsonErr = dec.Decode(&v)
-		return v.Orders
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		for _, order := range v.Orders {
+			i.trackOrder(order)
+		}
+		return v.Orders, nil
 	}
 
-	var v errorResult
-	jsonErr = dec.Decode(&v)
-	i.setErr(apiError(v.Error, res.Status))
-
-	i.setErr(jsonErr)
-	return nil
+	var e errorResult
+	if err := dec.Decode(&e); err != nil {
+		return nil, err
+	}
+	return nil, &Error{StatusCode: res.StatusCode, APIMessage: e.Error, Op: "QueryOrders", Retryable: retryableStatus(res.StatusCode)}
 }
@@ -0,0 +1,161 @@
+package api
+
+import "context"
+
+func (i *Instance) rememberClientOrderID(clientOrderID string, orderID int) {
+	i.Lock()
+	if i.clientOrderIDs == nil {
+		i.clientOrderIDs = make(map[string]int)
+	}
+	i.clientOrderIDs[clientOrderID] = orderID
+	i.Unlock()
+}
+
+//OrderIDForClientOrderID returns the exchange order ID last associated with clientOrderID on this Instance,
+//or 0 and false if it is unknown.
+func (i *Instance) OrderIDForClientOrderID(clientOrderID string) (int, bool) {
+	i.RLock()
+	defer i.RUnlock()
+	orderID, ok := i.clientOrderIDs[clientOrderID]
+	return orderID, ok
+}
+
+//forgetClientOrderIDs clears i's remembered client order IDs, called from Instance.Close.
+func (i *Instance) forgetClientOrderIDs() {
+	i.Lock()
+	i.clientOrderIDs = nil
+	i.Unlock()
+}
+
+//OrderRequestBuilder builds and submits a single order without mutating the Instance it was created from.
+//Symbol, Venue and Account default to the Instance's current values but can be overridden per-order, which
+//lets a single Instance be used concurrently to trade more than one stock/venue without racing on its fields.
+//Obtain one via Instance.NewOrderRequest.
+type OrderRequestBuilder struct {
+	i             *Instance
+	symbol        string
+	venue         string
+	account       string
+	price         int
+	quantity      int
+	direction     orderDirection
+	orderType     orderType
+	clientOrderID string
+}
+
+//NewOrderRequest returns an OrderRequestBuilder pre-filled with the Instance's current symbol, venue and account.
+func (i *Instance) NewOrderRequest() *OrderRequestBuilder {
+	i.RLock()
+	defer i.RUnlock()
+	return &OrderRequestBuilder{
+		i:       i,
+		symbol:  i.symbol,
+		venue:   i.venue,
+		account: i.account,
+	}
+}
+
+//Symbol overrides the stock symbol the order is submitted for.
+func (b *OrderRequestBuilder) Symbol(symbol string) *OrderRequestBuilder {
+	b.symbol = symbol
+	return b
+}
+
+//Venue overrides the venue the order is submitted to.
+func (b *OrderRequestBuilder) Venue(venue string) *OrderRequestBuilder {
+	b.venue = venue
+	return b
+}
+
+//Account overrides the account the order is submitted under.
+func (b *OrderRequestBuilder) Account(account string) *OrderRequestBuilder {
+	b.account = account
+	return b
+}
+
+//Price sets the limit price of the order, in cents.
+func (b *OrderRequestBuilder) Price(price int) *OrderRequestBuilder {
+	b.price = price
+	return b
+}
+
+//Quantity sets the number of shares to buy or sell.
+func (b *OrderRequestBuilder) Quantity(quantity int) *OrderRequestBuilder {
+	b.quantity = quantity
+	return b
+}
+
+//Buy sets the order direction to buy.
+func (b *OrderRequestBuilder) Buy() *OrderRequestBuilder {
+	b.direction = Buy
+	return b
+}
+
+//Sell sets the order direction to sell.
+func (b *OrderRequestBuilder) Sell() *OrderRequestBuilder {
+	b.direction = Sell
+	return b
+}
+
+//Direction sets the order direction explicitly, for callers that already hold an orderDirection value.
+func (b *OrderRequestBuilder) Direction(direction orderDirection) *OrderRequestBuilder {
+	b.direction = direction
+	return b
+}
+
+//Limit sets the order type to limit.
+func (b *OrderRequestBuilder) Limit() *OrderRequestBuilder {
+	b.orderType = Limit
+	return b
+}
+
+//Market sets the order type to market.
+func (b *OrderRequestBuilder) Market() *OrderRequestBuilder {
+	b.orderType = Market
+	return b
+}
+
+//FillOrKill sets the order type to fill-or-kill.
+func (b *OrderRequestBuilder) FillOrKill() *OrderRequestBuilder {
+	b.orderType = FillOrKill
+	return b
+}
+
+//ImmediateOrCancel sets the order type to immediate-or-cancel.
+func (b *OrderRequestBuilder) ImmediateOrCancel() *OrderRequestBuilder {
+	b.orderType = ImmediateOrCancel
+	return b
+}
+
+//ClientOrderID attaches a caller-supplied ID that the library remembers locally and stamps onto the returned
+//Order, so retries of the same logical order can be recognized by the caller even though the Stockfighter API
+//itself has no notion of client order IDs.
+func (b *OrderRequestBuilder) ClientOrderID(id string) *OrderRequestBuilder {
+	b.clientOrderID = id
+	return b
+}
+
+//Do submits the order built so far and returns the resulting Order, or an error if the request could not be
+//built or failed. Unlike Instance.NewOrder, Do returns the error directly instead of stashing it on the Instance.
+func (b *OrderRequestBuilder) Do(ctx context.Context) (Order, error) {
+	req := orderRequest{
+		Account:   b.account,
+		Venue:     b.venue,
+		Symbol:    b.symbol,
+		Price:     b.price,
+		Quantity:  b.quantity,
+		Direction: b.direction,
+		OrderType: b.orderType,
+	}
+	url := baseURL + "venues/" + b.venue + "/stocks/" + b.symbol + "/orders"
+
+	v, err := b.i.doOrderRequest(ctx, "POST", url, req, b.clientOrderID)
+	if err != nil {
+		return v, err
+	}
+
+	if b.clientOrderID != "" {
+		b.i.rememberClientOrderID(b.clientOrderID, v.ID)
+	}
+	return v, nil
+}
@@ -0,0 +1,43 @@
+package api
+
+//OrderState summarizes an Order's lifecycle more precisely than the raw Open flag, distinguishing a fully
+//filled order from one that was canceled, rejected, or expired unfilled (the latter for IOC/FOK orders).
+type OrderState string
+
+const (
+	StateOpen     OrderState = "open"
+	StateFilled   OrderState = "filled"
+	StateCanceled OrderState = "canceled"
+	StateRejected OrderState = "rejected"
+	StateExpired  OrderState = "expired"
+)
+
+//deriveOrderState computes the OrderState implied by o's Open/TotalFilled/OriginalQuantity/OrderType fields,
+//as decoded from the API. It does not distinguish rejected from canceled at the API level, since both
+//surface identically (Open==false, not fully filled, not an IOC/FOK order); callers that placed the order
+//themselves can tell the two apart from whether NewOrder ever returned an ID.
+func deriveOrderState(o Order) OrderState {
+	if o.Open {
+		return StateOpen
+	}
+	if o.TotalFilled == o.OriginalQuantity {
+		return StateFilled
+	}
+	if o.TotalFilled < o.OriginalQuantity && (o.OrderType == FillOrKill || o.OrderType == ImmediateOrCancel) {
+		return StateExpired
+	}
+	return StateCanceled
+}
+
+//AveragePrice returns the quantity-weighted average fill price across o.Fills, or 0 if there are no fills.
+func (o Order) AveragePrice() int {
+	var totalValue, totalQty int
+	for _, f := range o.Fills {
+		totalValue += f.Price * f.Quantity
+		totalQty += f.Quantity
+	}
+	if totalQty == 0 {
+		return 0
+	}
+	return totalValue / totalQty
+}
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//defaultReconcileInterval is how often an enabled OrderBook re-fetches AccountOrderStatus to repair any
+//updates missed on the executions stream (a dropped message, a reconnect, ...).
+const defaultReconcileInterval = 30 * time.Second
+
+//OrderEventType identifies what changed about an order in an OrderUpdate.
+type OrderEventType string
+
+const (
+	OrderUpdated  OrderEventType = "updated"
+	OrderFilled   OrderEventType = "filled"
+	OrderCanceled OrderEventType = "canceled"
+)
+
+//OrderUpdate is emitted on the channel passed to EnableOrderTracking whenever a tracked order changes.
+type OrderUpdate struct {
+	Type  OrderEventType
+	Order Order
+}
+
+//ExecutionMessage is a single message from the account's executions websocket stream, reporting a fill
+//against one of its orders. Order already reflects the fill in its own Fills/TotalFilled/Open/State.
+type ExecutionMessage struct {
+	Ok               bool      `json:"ok"`
+	Error            string    `json:"error"`
+	Account          string    `json:"account"`
+	Venue            string    `json:"venue"`
+	Order            Order     `json:"order"`
+	StandingID       int       `json:"standingId"`
+	IncomingID       int       `json:"incomingId"`
+	Price            int       `json:"price"`
+	Filled           int       `json:"filled"`
+	FilledAt         time.Time `json:"filledAt"`
+	StandingComplete bool      `json:"standingComplete"`
+	IncomingComplete bool      `json:"incomingComplete"`
+}
+
+//Position is an account's net holding in a symbol as implied by the fills of its tracked orders.
+type Position struct {
+	Symbol      string
+	NetQuantity int //positive for net long, negative for net short.
+	AverageCost int //quantity-weighted average fill price of the contributing fills, in cents.
+}
+
+//OrderBook is a local cache of an account's orders, kept current by applying fills from the executions
+//websocket stream as they arrive and by periodically reconciling against AccountOrderStatus. It lets a
+//strategy react to fills via events instead of polling OrderStatus. Obtain one via Instance.EnableOrderTracking.
+type OrderBook struct {
+	i      *Instance
+	events chan<- OrderUpdate
+
+	mu     sync.RWMutex
+	orders map[int]Order
+	closed bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+//EnableOrderTracking starts a local OrderBook for i: it indexes every Order returned by NewOrder,
+//CancelOrder, OrderStatus, AccountOrderStatus and StockOrderStatus, applies fills from the account's
+//executions websocket stream as they arrive, and periodically reconciles against AccountOrderStatus. Order
+//lifecycle changes are sent to events, which the caller should drain promptly to avoid blocking delivery; a
+//buffered channel is recommended. Calling EnableOrderTracking again replaces the previous OrderBook.
+func (i *Instance) EnableOrderTracking(events chan<- OrderUpdate) *OrderBook {
+	ctx, cancel := context.WithCancel(context.Background())
+	ob := &OrderBook{
+		i:      i,
+		events: events,
+		orders: make(map[int]Order),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	i.Lock()
+	i.orderBook = ob
+	i.Unlock()
+
+	go ob.run(ctx)
+	return ob
+}
+
+//Close stops the OrderBook's websocket subscription and reconciliation loop and stops it from receiving
+//further updates via trackOrder. Further updates are not applied after Close returns: closed is set before
+//cancel/wait so a trackOrder call racing with Close either lands before closed is observed (harmless: index
+//just updates the already-torn-down cache) or is dropped by index's closed check, rather than sending on
+//events after the caller has stopped draining it.
+func (ob *OrderBook) Close() {
+	ob.mu.Lock()
+	ob.closed = true
+	ob.mu.Unlock()
+
+	ob.cancel()
+	<-ob.done
+
+	ob.i.Lock()
+	if ob.i.orderBook == ob {
+		ob.i.orderBook = nil
+	}
+	ob.i.Unlock()
+}
+
+func (ob *OrderBook) run(ctx context.Context) {
+	defer close(ob.done)
+
+	executions, err := ob.i.ExecutionsForAccount(ctx)
+	if err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(defaultReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-executions:
+			if !ok {
+				return
+			}
+			ob.applyFill(msg)
+		case <-ticker.C:
+			ob.reconcile(ctx)
+		}
+	}
+}
+
+//index stores or updates order in the cache and emits an OrderUpdated/OrderFilled/OrderCanceled event as
+//appropriate. It is called both from the websocket/reconcile paths and, via trackOrder, whenever a
+//NewOrder/CancelOrder/OrderStatus call returns a fresh Order while tracking is enabled — including from
+//directly inside those HTTP calls, so the send to events must never block: a caller that isn't actively
+//draining events would otherwise wedge every order call the library makes on i.
+func (ob *OrderBook) index(order Order) {
+	ob.mu.Lock()
+	if ob.closed {
+		ob.mu.Unlock()
+		return
+	}
+	ob.orders[order.ID] = order
+	ob.mu.Unlock()
+
+	if ob.events == nil {
+		return
+	}
+	var eventType OrderEventType
+	switch order.State {
+	case StateFilled:
+		eventType = OrderFilled
+	case StateCanceled, StateExpired, StateRejected:
+		eventType = OrderCanceled
+	default:
+		eventType = OrderUpdated
+	}
+
+	select {
+	case ob.events <- OrderUpdate{Type: eventType, Order: order}:
+	default:
+	}
+}
+
+//applyFill indexes msg.Order as-is: like the Order values reconcile() gets from AccountOrderStatusContext,
+//msg.Order already reflects the fill in its own Fills/TotalFilled/Open, so there is nothing to merge here —
+//doing so would double-count the fill.
+func (ob *OrderBook) applyFill(msg ExecutionMessage) {
+	ob.index(msg.Order)
+}
+
+func (ob *OrderBook) reconcile(ctx context.Context) {
+	orders, err := ob.i.AccountOrderStatusContext(ctx)
+	if err != nil {
+		return
+	}
+	for _, order := range orders {
+		ob.index(order)
+	}
+}
+
+//Get returns the cached Order for id, or false if id is not tracked.
+func (ob *OrderBook) Get(id int) (Order, bool) {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+	order, ok := ob.orders[id]
+	return order, ok
+}
+
+//Open returns every tracked order currently in StateOpen.
+func (ob *OrderBook) Open() []Order {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	open := make([]Order, 0, len(ob.orders))
+	for _, order := range ob.orders {
+		if order.State == StateOpen {
+			open = append(open, order)
+		}
+	}
+	return open
+}
+
+//Position returns the net signed quantity and average cost for symbol across every tracked order's fills:
+//buys contribute positive quantity, sells negative.
+func (ob *OrderBook) Position(symbol string) Position {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	pos := Position{Symbol: symbol}
+	var totalValue, totalQty int
+	for _, order := range ob.orders {
+		if order.Symbol != symbol {
+			continue
+		}
+		sign := 1
+		if order.Direction == Sell {
+			sign = -1
+		}
+		for _, f := range order.Fills {
+			pos.NetQuantity += sign * f.Quantity
+			totalValue += f.Price * f.Quantity
+			totalQty += f.Quantity
+		}
+	}
+	if totalQty != 0 {
+		pos.AverageCost = totalValue / totalQty
+	}
+	return pos
+}
+
+//trackOrder feeds order into i's OrderBook, if tracking is enabled via EnableOrderTracking. It is a no-op
+//otherwise, so order.go/bulk.go can call it unconditionally after every order-returning API call.
+func (i *Instance) trackOrder(order Order) {
+	i.RLock()
+	ob := i.orderBook
+	i.RUnlock()
+	if ob == nil {
+		return
+	}
+	ob.index(order)
+}
+
+//stopOrderTracking stops and untracks i's OrderBook, if one was started via EnableOrderTracking. It is a
+//no-op otherwise, called from Instance.Close.
+func (i *Instance) stopOrderTracking() {
+	i.RLock()
+	ob := i.orderBook
+	i.RUnlock()
+	if ob == nil {
+		return
+	}
+	ob.Close()
+}
+
+//Close stops any OrderBook started on i via EnableOrderTracking (its websocket subscription and reconciliation
+//goroutine, via OrderBook.Close) and clears i's remembered client order IDs. Call Close when done with i,
+//particularly if EnableOrderTracking was used, since otherwise its goroutine runs for the life of the process.
+func (i *Instance) Close() {
+	i.forgetClientOrderIDs()
+	i.stopOrderTracking()
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestOrderBook() *OrderBook {
+	return &OrderBook{
+		i:      NewTestInstance(),
+		orders: make(map[int]Order),
+	}
+}
+
+func TestOrderBookIndexGetOpen(t *testing.T) {
+	ob := newTestOrderBook()
+
+	open := Order{ID: 1, Symbol: "FOOBAR", Open: true, State: StateOpen}
+	filled := Order{ID: 2, Symbol: "FOOBAR", Open: false, TotalFilled: 1, OriginalQuantity: 1, State: StateFilled}
+	ob.index(open)
+	ob.index(filled)
+
+	if got, ok := ob.Get(1); !ok || got.ID != 1 {
+		t.Fatalf("Get(1) = %+v, %v", got, ok)
+	}
+	if _, ok := ob.Get(99); ok {
+		t.Fatalf("Get(99) unexpectedly found an order")
+	}
+
+	openOrders := ob.Open()
+	if len(openOrders) != 1 || openOrders[0].ID != 1 {
+		t.Fatalf("Open() = %+v, want just order 1", openOrders)
+	}
+}
+
+func TestOrderBookApplyFillDoesNotDoubleCount(t *testing.T) {
+	ob := newTestOrderBook()
+
+	order := Order{
+		ID: 1, Symbol: "FOOBAR", Direction: Buy, Open: true,
+		Fills: []Fill{{Price: 100, Quantity: 5, TS: time.Now()}},
+	}
+	ob.applyFill(ExecutionMessage{Order: order})
+
+	pos := ob.Position("FOOBAR")
+	if pos.NetQuantity != 5 {
+		t.Fatalf("NetQuantity = %d, want 5", pos.NetQuantity)
+	}
+
+	//A later message reporting the same cumulative fills must not add to the position again.
+	ob.applyFill(ExecutionMessage{Order: order})
+	pos = ob.Position("FOOBAR")
+	if pos.NetQuantity != 5 {
+		t.Fatalf("NetQuantity after repeat = %d, want 5 (no double count)", pos.NetQuantity)
+	}
+}
+
+func TestOrderBookIndexAfterCloseIsNoop(t *testing.T) {
+	ob := newTestOrderBook()
+	ob.closed = true
+
+	ob.index(Order{ID: 1, Symbol: "FOOBAR"})
+
+	if _, ok := ob.Get(1); ok {
+		t.Fatalf("index() stored an order after closed was set")
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"sort"
+)
+
+//OpenOrdersOptions filters the result of QueryOpenOrders/QueryClosedOrders. Zero-valued fields are ignored:
+//an empty Symbol or Direction matches every symbol/direction, and MinPrice/MaxPrice/SinceID/Limit of 0 are
+//treated as "no bound" except where noted.
+type OpenOrdersOptions struct {
+	Symbol    string
+	Direction orderDirection
+	MinPrice  int //ignored if 0.
+	MaxPrice  int //ignored if 0.
+	SinceID   int //only orders with ID > SinceID are returned.
+	Limit     int //if > 0, caps the number of orders returned after filtering.
+}
+
+func (o OpenOrdersOptions) matches(order Order) bool {
+	if o.Symbol != "" && order.Symbol != o.Symbol {
+		return false
+	}
+	if o.Direction != "" && order.Direction != o.Direction {
+		return false
+	}
+	if o.MinPrice != 0 && order.Price < o.MinPrice {
+		return false
+	}
+	if o.MaxPrice != 0 && order.Price > o.MaxPrice {
+		return false
+	}
+	if order.ID <= o.SinceID {
+		return false
+	}
+	return true
+}
+
+//QueryOpenOrders returns the account's currently-open orders matching opt, sorted by ID ascending. The
+//Stockfighter API has no server-side filtering, so this fetches every order via AccountOrderStatusContext and
+//filters/sorts client-side.
+func (i *Instance) QueryOpenOrders(ctx context.Context, opt OpenOrdersOptions) ([]Order, error) {
+	return i.queryOrders(ctx, opt, func(o Order) bool { return o.State == StateOpen })
+}
+
+//QueryClosedOrders returns the account's no-longer-open orders matching opt, sorted by ID ascending. It is
+//the complement of QueryOpenOrders.
+func (i *Instance) QueryClosedOrders(ctx context.Context, opt OpenOrdersOptions) ([]Order, error) {
+	return i.queryOrders(ctx, opt, func(o Order) bool { return o.State != StateOpen })
+}
+
+func (i *Instance) queryOrders(ctx context.Context, opt OpenOrdersOptions, stateMatches func(Order) bool) ([]Order, error) {
+	orders, err := i.AccountOrderStatusContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]Order, 0, len(orders))
+	for _, o := range orders {
+		if stateMatches(o) && opt.matches(o) {
+			matched = append(matched, o)
+		}
+	}
+
+	sort.Slice(matched, func(a, b int) bool { return matched[a].ID < matched[b].ID })
+
+	if opt.Limit > 0 && len(matched) > opt.Limit {
+		matched = matched[:opt.Limit]
+	}
+	return matched, nil
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+//baseWSURL is the root of the Stockfighter executions websocket API.
+const baseWSURL = "wss://api.stockfighter.io/ob/api/ws/"
+
+//ExecutionsForAccount subscribes to the current account's executions stream on the current venue and returns
+//a channel of ExecutionMessage, used by OrderBook to learn about fills as they happen. The channel is closed
+//when ctx is canceled or the connection is dropped by the server; callers should treat a closed channel as
+//"resubscribe if you still care", not as an error. Most callers should use EnableOrderTracking instead of
+//calling ExecutionsForAccount directly.
+//See https://starfighter.readme.io/docs/executions-fills-websocket for further info about the actual API call.
+func (i *Instance) ExecutionsForAccount(ctx context.Context) (<-chan ExecutionMessage, error) {
+	i.RLock()
+	url := baseWSURL + i.account + "/venues/" + i.venue + "/executions"
+	i.RUnlock()
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, http.Header{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan ExecutionMessage)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	go func() {
+		defer close(out)
+		defer close(done)
+		defer conn.Close()
+
+		for {
+			var msg ExecutionMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/ianberinger/stockfighter/api"
+	"stockfighter/api"
 )
 
 const (